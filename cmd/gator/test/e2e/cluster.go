@@ -0,0 +1,226 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/open-policy-agent/frameworks/constraint/pkg/apis/templates/v1beta1"
+	"github.com/open-policy-agent/gatekeeper/pkg/gktest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// constraintApplyRetries/constraintApplyBackoff bound how long
+// clusterEvaluator waits for a freshly-applied ConstraintTemplate's CRD to
+// be established before giving up on applying the Constraint that depends
+// on it.
+const (
+	constraintApplyRetries = 10
+	constraintApplyBackoff = 2 * time.Second
+)
+
+// serverErrorPrefix is what kubectl prints when the apiserver itself
+// rejected a request - including an admission webhook denial - as opposed
+// to a client-side failure such as an unreachable cluster, a bad context,
+// or a malformed fixture, all of which kubectl reports without ever
+// reaching the server.
+const serverErrorPrefix = "Error from server"
+
+// gatekeeperManifestURL is the hosted manifest published for each
+// Gatekeeper release, e.g.
+// https://raw.githubusercontent.com/open-policy-agent/gatekeeper/v3.14.0/deploy/gatekeeper.yaml.
+const gatekeeperManifestURL = "https://raw.githubusercontent.com/open-policy-agent/gatekeeper/%s/deploy/gatekeeper.yaml"
+
+// kindCluster wraps a kind cluster that gator test e2e provisions (or
+// attaches to) so it can submit fixtures through the cluster's real
+// admission webhook.
+type kindCluster struct {
+	name    string
+	reused  bool
+	kubectl func(stdin []byte, args ...string) ([]byte, error)
+}
+
+// newKindCluster provisions a kind cluster running image, or attaches to
+// the existing "gator-e2e" cluster if reuse is true and it already exists.
+func newKindCluster(image string, reuse bool) (*kindCluster, error) {
+	const name = "gator-e2e"
+
+	c := &kindCluster{
+		name:   name,
+		reused: reuse && clusterExists(name),
+		kubectl: func(stdin []byte, args ...string) ([]byte, error) {
+			return runCommand(stdin, "kubectl", append([]string{"--context", "kind-" + name}, args...)...)
+		},
+	}
+
+	if c.reused {
+		return c, nil
+	}
+
+	createArgs := []string{"create", "cluster", "--name", name}
+	if image != "" {
+		createArgs = append(createArgs, "--image", image)
+	}
+	if _, err := runCommand(nil, "kind", createArgs...); err != nil {
+		return nil, fmt.Errorf("creating kind cluster %q: %w", name, err)
+	}
+
+	return c, nil
+}
+
+func clusterExists(name string) bool {
+	out, err := runCommand(nil, "kind", "get", "clusters")
+	if err != nil {
+		return false
+	}
+	return clusterListContains(out, name)
+}
+
+// clusterListContains reports whether name appears as its own line in out,
+// the output of `kind get clusters` (one cluster name per line).
+func clusterListContains(out []byte, name string) bool {
+	for _, line := range bytes.Split(out, []byte("\n")) {
+		if string(bytes.TrimSpace(line)) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// InstallGatekeeper applies the hosted manifest for version to the
+// cluster and waits for the controller-manager to become available.
+func (c *kindCluster) InstallGatekeeper(version string) error {
+	manifest := fmt.Sprintf(gatekeeperManifestURL, version)
+
+	if _, err := c.kubectl(nil, "apply", "-f", manifest); err != nil {
+		return fmt.Errorf("applying %s: %w", manifest, err)
+	}
+
+	_, err := c.kubectl(nil, "wait", "--for=condition=Available",
+		"--timeout=120s", "-n", "gatekeeper-system",
+		"deployment/gatekeeper-controller-manager")
+	return err
+}
+
+// Delete tears down the cluster unless it was attached to via
+// --reuse-cluster, in which case the caller didn't provision it and
+// shouldn't remove it out from under them.
+func (c *kindCluster) Delete() {
+	if c.reused {
+		return
+	}
+	_, _ = runCommand(nil, "kind", "delete", "cluster", "--name", c.name)
+}
+
+// runSuiteAgainstCluster dry-run creates each of a suite's test fixtures
+// through the real admission webhook, mapping the resulting
+// AdmissionResponse back onto the suite's expected violations.
+// clusterEvaluator installs (and tears down) only the template/constraint
+// pair each test is reviewed against, so suites are not responsible for
+// pre-installing anything onto the cluster.
+func runSuiteAgainstCluster(cluster *kindCluster, s gktest.Suite, filter gktest.Filter) []gktest.Result {
+	fileSystem := os.DirFS("/")
+
+	return s.Run(fileSystem, filter, &clusterEvaluator{cluster: cluster})
+}
+
+// clusterEvaluator is a gktest.Evaluator backed by dry-run creates against
+// a real cluster's admission webhook, rather than in-process evaluation.
+type clusterEvaluator struct {
+	cluster *kindCluster
+}
+
+// NewReview implements gktest.Evaluator. It installs template and
+// constraint for the duration of this one review and tears them down
+// afterward, matching the per-review isolation LocalEvaluator provides:
+// a suite with more than one constraint under test never has two
+// constraints installed on the cluster at once.
+func (e *clusterEvaluator) NewReview(_ context.Context, template *v1beta1.ConstraintTemplate, constraint *unstructured.Unstructured, object *unstructured.Unstructured) (*gktest.Result, error) {
+	if err := e.cluster.applyConstraint(template, constraint); err != nil {
+		return nil, fmt.Errorf("installing %s %q for review: %w", constraint.GetKind(), constraint.GetName(), err)
+	}
+	defer e.cluster.deleteConstraint(template, constraint)
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling object fixture: %w", err)
+	}
+
+	out, err := e.cluster.kubectl(data, "create", "--dry-run=server", "-f", "-")
+	if err == nil {
+		return &gktest.Result{}, nil
+	}
+
+	msg := strings.TrimSpace(string(out))
+	if !strings.HasPrefix(msg, serverErrorPrefix) {
+		// kubectl never got a response to judge: an unreachable cluster, a
+		// bad context, an auth failure, or a malformed fixture. None of
+		// those are the policy's verdict on the object, so they must not be
+		// folded into a Result - that would report a transport blip as a
+		// passing or failing test.
+		return nil, fmt.Errorf("dry-run creating fixture: %w", err)
+	}
+
+	// The apiserver rejected the request - either an admission webhook
+	// denial or another server-side validation failure - which is exactly
+	// what this Result is meant to capture.
+	return &gktest.Result{Error: errors.New(msg)}, nil
+}
+
+func (e *clusterEvaluator) Close() error {
+	return nil
+}
+
+// applyConstraint installs template and the Constraint it defines onto the
+// cluster. The Constraint apply is retried for a while because the CRD it
+// instantiates only exists once Gatekeeper's controller has reconciled the
+// freshly-applied ConstraintTemplate.
+func (c *kindCluster) applyConstraint(template *v1beta1.ConstraintTemplate, constraint *unstructured.Unstructured) error {
+	templateData, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("marshaling template %q: %w", template.GetName(), err)
+	}
+	if _, err := c.kubectl(templateData, "apply", "-f", "-"); err != nil {
+		return fmt.Errorf("applying template %q: %w", template.GetName(), err)
+	}
+
+	constraintData, err := json.Marshal(constraint)
+	if err != nil {
+		return fmt.Errorf("marshaling constraint %q: %w", constraint.GetName(), err)
+	}
+
+	var applyErr error
+	for attempt := 0; attempt < constraintApplyRetries; attempt++ {
+		if _, applyErr = c.kubectl(constraintData, "apply", "-f", "-"); applyErr == nil {
+			return nil
+		}
+		time.Sleep(constraintApplyBackoff)
+	}
+	return fmt.Errorf("applying constraint %q: %w", constraint.GetName(), applyErr)
+}
+
+// deleteConstraint removes constraint and template from the cluster so the
+// next review starts from a clean slate instead of accumulating every
+// constraint a suite has exercised so far.
+func (c *kindCluster) deleteConstraint(template *v1beta1.ConstraintTemplate, constraint *unstructured.Unstructured) {
+	_, _ = c.kubectl(nil, "delete", "--ignore-not-found", constraint.GetKind(), constraint.GetName())
+	_, _ = c.kubectl(nil, "delete", "--ignore-not-found", "constrainttemplate", template.GetName())
+}
+
+func runCommand(stdin []byte, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return out, fmt.Errorf("%s %v: %w: %s", name, args, err, out)
+	}
+	return out, nil
+}
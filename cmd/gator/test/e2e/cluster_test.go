@@ -0,0 +1,122 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/frameworks/constraint/pkg/apis/templates/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// fakeKubectl returns a kindCluster.kubectl implementation that answers
+// apply/delete calls with applyErr and the dry-run create call with
+// (createOutput, createErr), without shelling out to a real kubectl.
+func fakeKubectl(applyErr error, createOutput []byte, createErr error) func([]byte, ...string) ([]byte, error) {
+	return func(_ []byte, args ...string) ([]byte, error) {
+		if len(args) == 0 {
+			return nil, errors.New("no args")
+		}
+		switch args[0] {
+		case "apply":
+			return nil, applyErr
+		case "create":
+			return createOutput, createErr
+		case "delete":
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("unexpected kubectl args: %v", args)
+		}
+	}
+}
+
+func testTemplateAndConstraint() (*v1beta1.ConstraintTemplate, *unstructured.Unstructured) {
+	template := &v1beta1.ConstraintTemplate{}
+	template.SetName("k8srequiredlabels")
+
+	constraint := &unstructured.Unstructured{}
+	constraint.SetKind("K8sRequiredLabels")
+	constraint.SetName("must-have-owner")
+
+	return template, constraint
+}
+
+func TestClusterEvaluator_NewReview(t *testing.T) {
+	template, constraint := testTemplateAndConstraint()
+	object := &unstructured.Unstructured{}
+	object.SetKind("Pod")
+	object.SetName("my-pod")
+
+	t.Run("object is allowed", func(t *testing.T) {
+		cluster := &kindCluster{kubectl: fakeKubectl(nil, nil, nil)}
+		e := &clusterEvaluator{cluster: cluster}
+
+		result, err := e.NewReview(context.Background(), template, constraint, object)
+		if err != nil {
+			t.Fatalf("NewReview: %v", err)
+		}
+		if result.IsFailure() {
+			t.Errorf("got failing result %v, want a pass", result.Error)
+		}
+	})
+
+	t.Run("apiserver denies the request", func(t *testing.T) {
+		denial := `Error from server (Forbidden): admission webhook "validation.gatekeeper.sh" denied the request: [must-have-owner] you must provide labels: {"owner"}`
+		cluster := &kindCluster{kubectl: fakeKubectl(nil, []byte(denial), errors.New("exit status 1"))}
+		e := &clusterEvaluator{cluster: cluster}
+
+		result, err := e.NewReview(context.Background(), template, constraint, object)
+		if err != nil {
+			t.Fatalf("NewReview returned a hard error for a real admission denial: %v", err)
+		}
+		if !result.IsFailure() {
+			t.Fatal("got a passing result, want a denial")
+		}
+		if !strings.Contains(result.Error.Error(), "you must provide labels") {
+			t.Errorf("got error %q, want it to contain the denial message", result.Error)
+		}
+	})
+
+	t.Run("transport failure is a hard error, not a denial", func(t *testing.T) {
+		transportFailure := `Unable to connect to the server: dial tcp: lookup kind-gator-e2e: no such host`
+		cluster := &kindCluster{kubectl: fakeKubectl(nil, []byte(transportFailure), errors.New("exit status 1"))}
+		e := &clusterEvaluator{cluster: cluster}
+
+		result, err := e.NewReview(context.Background(), template, constraint, object)
+		if err == nil {
+			t.Fatalf("got a Result (%v), want a hard error for an unreachable cluster", result)
+		}
+	})
+
+	t.Run("failing to install the constraint is a hard error", func(t *testing.T) {
+		// The template apply fails outright (no retries), so this never hits
+		// applyConstraint's retry/backoff loop for the constraint itself.
+		cluster := &kindCluster{kubectl: func(_ []byte, args ...string) ([]byte, error) {
+			if args[0] == "apply" {
+				return nil, errors.New("applying template failed")
+			}
+			return nil, fmt.Errorf("unexpected kubectl args: %v", args)
+		}}
+		e := &clusterEvaluator{cluster: cluster}
+
+		if _, err := e.NewReview(context.Background(), template, constraint, object); err == nil {
+			t.Fatal("got nil error, want one when installing the constraint fails")
+		}
+	})
+}
+
+func TestClusterListContains(t *testing.T) {
+	out := []byte("gator-e2e\nkind\nother-cluster\n")
+
+	if !clusterListContains(out, "gator-e2e") {
+		t.Error("got false, want true for a cluster present in the list")
+	}
+	if clusterListContains(out, "missing-cluster") {
+		t.Error("got true, want false for a cluster absent from the list")
+	}
+	if clusterListContains(out, "kin") {
+		t.Error("got true for a substring match, want an exact line match")
+	}
+}
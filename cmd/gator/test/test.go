@@ -1,13 +1,16 @@
 package test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 
+	"github.com/open-policy-agent/gatekeeper/cmd/gator/test/e2e"
 	"github.com/open-policy-agent/gatekeeper/pkg/gktest"
+	"github.com/open-policy-agent/gatekeeper/pkg/gktest/reporter"
 	"github.com/spf13/cobra"
 )
 
@@ -26,14 +29,39 @@ const (
 
   # Run all tests that are either named "forbid-labels" or are
   # in suites named "forbid-labels".
-  gator test tests/... --run '^forbid-labels$'`
+  gator test tests/... --run '^forbid-labels$'
+
+  # Re-run matching suites whenever a test file under tests/ changes.
+  gator test tests/... --watch
+
+  # Emit JUnit XML for consumption by a CI dashboard.
+  gator test tests/... --output junit --output-file results.xml`
 )
 
-var run string
+var (
+	run          string
+	watch        bool
+	evaluatorArg string
+	evaluatorURL string
+	output       string
+	outputFile   string
+)
 
 func init() {
 	Cmd.Flags().StringVarP(&run, "run", "r", "",
 		`regular expression which filters tests to run by name`)
+	Cmd.Flags().BoolVarP(&watch, "watch", "w", false,
+		`watch the test path for changes and re-run matching suites`)
+	Cmd.Flags().StringVar(&evaluatorArg, "evaluator", "local",
+		`which Evaluator to review objects with: "local" evaluates in-process, "remote" sends review requests to --evaluator-url`)
+	Cmd.Flags().StringVar(&evaluatorURL, "evaluator-url", "",
+		`the OPA/Gatekeeper HTTP endpoint to send review requests to; required when --evaluator=remote`)
+	Cmd.Flags().StringVar(&output, "output", "human",
+		`how to format test results: "human", "json", or "junit"`)
+	Cmd.Flags().StringVar(&outputFile, "output-file", "",
+		`file to write results to instead of stdout`)
+
+	Cmd.AddCommand(e2e.Cmd)
 }
 
 // Cmd is the gator test subcommand.
@@ -73,22 +101,92 @@ func runE(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("compiling filter: %w", err)
 	}
 
-	return runSuites(fileSystem, suites, filter)
+	evaluator, err := newEvaluator()
+	if err != nil {
+		return fmt.Errorf("constructing evaluator: %w", err)
+	}
+	defer evaluator.Close()
+
+	runErr := runSuites(fileSystem, suites, filter, evaluator)
+
+	if !watch {
+		return runErr
+	}
+
+	// In watch mode a failing run is reported but shouldn't prevent the user
+	// from fixing it and triggering a re-run.
+	if runErr != nil {
+		fmt.Fprintln(os.Stderr, runErr)
+	}
+
+	return watchSuites(context.Background(), fileSystem, path, filter, evaluator)
+}
+
+// newEvaluator constructs the Evaluator selected by --evaluator.
+func newEvaluator() (gktest.Evaluator, error) {
+	switch evaluatorArg {
+	case "", "local":
+		return gktest.NewLocalEvaluator(), nil
+	case "remote":
+		if evaluatorURL == "" {
+			return nil, errors.New("--evaluator-url is required when --evaluator=remote")
+		}
+		return gktest.NewRemoteEvaluator(evaluatorURL), nil
+	default:
+		return nil, fmt.Errorf("unknown --evaluator %q: must be \"local\" or \"remote\"", evaluatorArg)
+	}
 }
 
-func runSuites(fileSystem fs.FS, suites []gktest.Suite, filter gktest.Filter) error {
+func runSuites(fileSystem fs.FS, suites []gktest.Suite, filter gktest.Filter, evaluator gktest.Evaluator) error {
+	rep, err := reporter.New(output)
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return fmt.Errorf("opening --output-file %q: %w", outputFile, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	// Formats that can emit a suite's results as soon as it finishes (human,
+	// json) do so immediately, for the same fast feedback gator test has
+	// always given on a large tree or under --watch. Formats that must see
+	// the whole run to produce one well-formed document (junit) fall back
+	// to buffering and reporting once at the end.
+	incremental, _ := rep.(reporter.SuiteReporter)
+
 	isFailure := false
+	var suiteResults []reporter.SuiteResult
 	for _, s := range suites {
 		if !filter.MatchesSuite(s) {
 			continue
 		}
 
-		results := s.Run(fileSystem, filter)
+		results := s.Run(fileSystem, filter, evaluator)
 		for _, result := range results {
 			if result.IsFailure() {
 				isFailure = true
 			}
-			fmt.Println(result.String())
+		}
+
+		suiteResult := reporter.SuiteResult{Path: s.Path(), Results: results}
+		if incremental != nil {
+			if err := incremental.ReportSuite(out, suiteResult); err != nil {
+				return fmt.Errorf("writing results: %w", err)
+			}
+			continue
+		}
+		suiteResults = append(suiteResults, suiteResult)
+	}
+
+	if incremental == nil {
+		if err := rep.Report(out, suiteResults); err != nil {
+			return fmt.Errorf("writing results: %w", err)
 		}
 	}
 
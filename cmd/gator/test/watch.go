@@ -0,0 +1,126 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/open-policy-agent/gatekeeper/pkg/gktest"
+)
+
+// watchDebounce is how long we wait for a burst of filesystem events to go
+// quiet before triggering a re-run. Editors frequently emit several events
+// (write, chmod, rename-into-place) for what a user experiences as a single
+// save.
+const watchDebounce = 200 * time.Millisecond
+
+// watchSuites re-runs the suites rooted at path every time a relevant file
+// underneath it changes, until ctx is canceled. It reloads suites from disk
+// on every run so that new test files are picked up automatically.
+func watchSuites(ctx context.Context, fileSystem fs.FS, path string, filter gktest.Filter, evaluator gktest.Evaluator) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := addWatches(watcher, path); err != nil {
+		return fmt.Errorf("watching %s: %w", path, err)
+	}
+
+	// rerunning guards against a debounced rerun firing while the previous
+	// one is still in flight - if a run takes longer than watchDebounce and
+	// the user saves again mid-run, a second rerun must wait rather than
+	// run concurrently, since both write to the same stdout/--output-file.
+	var rerunning sync.Mutex
+	rerun := func() {
+		rerunning.Lock()
+		defer rerunning.Unlock()
+
+		clearScreen()
+		fmt.Printf("--- rerunning tests: %s ---\n", time.Now().Format(time.RFC3339))
+
+		suites, err := gktest.ReadSuites(fileSystem, path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: listing test files: %v\n", err)
+			return
+		}
+
+		if err := runSuites(fileSystem, suites, filter, evaluator); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		}
+	}
+
+	var debounce *time.Timer
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// New directories need to be watched explicitly; fsnotify does
+			// not recurse on its own.
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = addWatches(watcher, event.Name)
+				}
+			}
+
+			if !isWatchedFile(event.Name) {
+				continue
+			}
+
+			if debounce != nil {
+				debounce.Stop()
+			}
+			debounce = time.AfterFunc(watchDebounce, rerun)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "watch error: %v\n", err)
+		}
+	}
+}
+
+// addWatches registers root and every directory beneath it with watcher.
+func addWatches(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return watcher.Add(p)
+		}
+		return nil
+	})
+}
+
+// isWatchedFile reports whether a change to name should trigger a re-run.
+func isWatchedFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".rego":
+		return true
+	default:
+		return false
+	}
+}
+
+// clearScreen resets the terminal between watch runs so output from the
+// previous run doesn't linger above the new results.
+func clearScreen() {
+	fmt.Print("\033[H\033[2J")
+}
@@ -0,0 +1,147 @@
+package gktest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/open-policy-agent/frameworks/constraint/pkg/apis/templates/v1beta1"
+	constraintclient "github.com/open-policy-agent/frameworks/constraint/pkg/client"
+	"github.com/open-policy-agent/gatekeeper/pkg/target"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Evaluator abstracts the backend that reviews an object against a
+// ConstraintTemplate/Constraint pair. Suite.Run calls into an Evaluator
+// rather than invoking the in-process OPA engine directly, so that gator
+// test can be pointed at different review backends (an in-process compiler,
+// a live OPA/Gatekeeper server, or - in the future - something like a WASM
+// or rego-repl runtime) without changing how suites are discovered or
+// filtered.
+//
+// This mirrors the pluggable FunctionRunner pattern kpt uses for its
+// hydration pipeline: callers own the lifecycle of the Evaluator and pass
+// the same instance to every suite, so whatever setup an implementation
+// does when it is constructed happens once per `gator test` invocation.
+// Work an implementation does inside NewReview (LocalEvaluator compiles
+// the template and constraint there) is repeated for every review.
+type Evaluator interface {
+	// NewReview evaluates object against constraint, which is enforced by
+	// template, and returns the review Result.
+	NewReview(ctx context.Context, template *v1beta1.ConstraintTemplate, constraint *unstructured.Unstructured, object *unstructured.Unstructured) (*Result, error)
+
+	// Close releases any resources held by the Evaluator (open connections,
+	// background processes). Callers must call Close exactly once when the
+	// Evaluator is no longer needed.
+	Close() error
+}
+
+// LocalEvaluator is the default Evaluator. It compiles templates and
+// constraints in-process using the same constraint framework Gatekeeper
+// uses at admission time.
+type LocalEvaluator struct{}
+
+// NewLocalEvaluator returns an Evaluator that reviews objects in-process.
+func NewLocalEvaluator() *LocalEvaluator {
+	return &LocalEvaluator{}
+}
+
+// NewReview implements Evaluator. Each call builds a fresh constraint
+// client scoped to the single template/constraint pair under test, which
+// keeps suites from leaking state between tests at the cost of some
+// redundant compilation. This matches the isolation gator test already
+// provides between suites.
+func (e *LocalEvaluator) NewReview(ctx context.Context, template *v1beta1.ConstraintTemplate, constraint *unstructured.Unstructured, object *unstructured.Unstructured) (*Result, error) {
+	backend, err := constraintclient.NewBackend(constraintclient.Targets(&target.K8sValidationTarget{}))
+	if err != nil {
+		return nil, fmt.Errorf("creating constraint backend: %w", err)
+	}
+
+	c, err := backend.NewClient(constraintclient.Targets(&target.K8sValidationTarget{}))
+	if err != nil {
+		return nil, fmt.Errorf("creating constraint client: %w", err)
+	}
+
+	if _, err := c.AddTemplate(ctx, template); err != nil {
+		return nil, fmt.Errorf("adding template %q: %w", template.GetName(), err)
+	}
+	if _, err := c.AddConstraint(ctx, constraint); err != nil {
+		return nil, fmt.Errorf("adding constraint %q: %w", constraint.GetName(), err)
+	}
+
+	review, err := c.Review(ctx, object)
+	if err != nil {
+		return nil, fmt.Errorf("reviewing object: %w", err)
+	}
+
+	return resultFromReview(review), nil
+}
+
+// Close implements Evaluator. The local evaluator holds no resources.
+func (e *LocalEvaluator) Close() error {
+	return nil
+}
+
+// RemoteEvaluator is an Evaluator that delegates review requests to a
+// running OPA or Gatekeeper HTTP endpoint. It is useful for verifying that
+// a suite's expectations hold against a live server - for example to
+// confirm identical behavior across Gatekeeper versions, or to test
+// against a cluster's actual constraint configuration.
+type RemoteEvaluator struct {
+	url    string
+	client *http.Client
+}
+
+// NewRemoteEvaluator returns an Evaluator that POSTs review requests to the
+// OPA/Gatekeeper endpoint at url.
+func NewRemoteEvaluator(url string) *RemoteEvaluator {
+	return &RemoteEvaluator{
+		url:    url,
+		client: http.DefaultClient,
+	}
+}
+
+// remoteReviewRequest is the body POSTed to the remote evaluator endpoint.
+type remoteReviewRequest struct {
+	Template   *v1beta1.ConstraintTemplate `json:"template"`
+	Constraint *unstructured.Unstructured  `json:"constraint"`
+	Object     *unstructured.Unstructured  `json:"object"`
+}
+
+// NewReview implements Evaluator.
+func (e *RemoteEvaluator) NewReview(ctx context.Context, template *v1beta1.ConstraintTemplate, constraint *unstructured.Unstructured, object *unstructured.Unstructured) (*Result, error) {
+	body, err := json.Marshal(remoteReviewRequest{Template: template, Constraint: constraint, Object: object})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling review request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("building review request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling remote evaluator %q: %w", e.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote evaluator %q returned status %d", e.url, resp.StatusCode)
+	}
+
+	result := &Result{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, fmt.Errorf("decoding response from %q: %w", e.url, err)
+	}
+	return result, nil
+}
+
+// Close implements Evaluator. The remote evaluator holds no persistent
+// connections beyond the shared http.Client.
+func (e *RemoteEvaluator) Close() error {
+	return nil
+}
@@ -0,0 +1,92 @@
+package gktest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-policy-agent/frameworks/constraint/pkg/apis/templates/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRemoteEvaluator_NewReview(t *testing.T) {
+	template := &v1beta1.ConstraintTemplate{}
+	template.SetName("my-template")
+	constraint := &unstructured.Unstructured{}
+	constraint.SetKind("K8sRequiredLabels")
+	constraint.SetName("my-constraint")
+	object := &unstructured.Unstructured{}
+	object.SetKind("Pod")
+	object.SetName("my-object")
+
+	t.Run("round trips a review through the remote endpoint", func(t *testing.T) {
+		var gotReq remoteReviewRequest
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				t.Errorf("got method %s, want POST", r.Method)
+			}
+			if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("got Content-Type %q, want application/json", ct)
+			}
+			if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+				t.Fatalf("decoding request body: %v", err)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(&Result{
+				SuitePath: "tests/suite.yaml",
+				TestName:  "denied",
+				Elapsed:   500 * time.Microsecond,
+			})
+		}))
+		defer srv.Close()
+
+		evaluator := NewRemoteEvaluator(srv.URL)
+		got, err := evaluator.NewReview(context.Background(), template, constraint, object)
+		if err != nil {
+			t.Fatalf("NewReview: %v", err)
+		}
+
+		want := &Result{SuitePath: "tests/suite.yaml", TestName: "denied", Elapsed: 500 * time.Microsecond}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("unexpected result: %s", diff)
+		}
+		if gotReq.Template.GetName() != "my-template" {
+			t.Errorf("got request template %q, want my-template", gotReq.Template.GetName())
+		}
+		if gotReq.Constraint.GetName() != "my-constraint" {
+			t.Errorf("got request constraint %q, want my-constraint", gotReq.Constraint.GetName())
+		}
+		if gotReq.Object.GetName() != "my-object" {
+			t.Errorf("got request object %q, want my-object", gotReq.Object.GetName())
+		}
+	})
+
+	t.Run("non-200 response is an error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		evaluator := NewRemoteEvaluator(srv.URL)
+		if _, err := evaluator.NewReview(context.Background(), template, constraint, object); err == nil {
+			t.Fatal("got nil error, want one for a non-200 response")
+		}
+	})
+
+	t.Run("malformed response body is an error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("not json"))
+		}))
+		defer srv.Close()
+
+		evaluator := NewRemoteEvaluator(srv.URL)
+		if _, err := evaluator.NewReview(context.Background(), template, constraint, object); err == nil {
+			t.Fatal("got nil error, want one for an undecodable response body")
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package gktest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// filterSeparator splits a --run pattern into a suite-name regex and a
+// test-name regex, e.g. "forbid-labels//" (suites) or "//nginx-deployment"
+// (tests). A pattern with no "//" is matched against both the suite name
+// and the test name, so a bare name finds either a suite or a test of that
+// name in any suite.
+const filterSeparator = "//"
+
+// Filter selects which suites/tests `gator test` runs, compiled from the
+// --run flag.
+type Filter struct {
+	suiteRE *regexp.Regexp
+	testRE  *regexp.Regexp
+	bareRE  *regexp.Regexp
+}
+
+// NewFilter compiles run into a Filter. An empty run matches everything.
+func NewFilter(run string) (Filter, error) {
+	if run == "" {
+		return Filter{}, nil
+	}
+
+	if suitePattern, testPattern, ok := strings.Cut(run, filterSeparator); ok {
+		suiteRE, err := regexp.Compile(suitePattern)
+		if err != nil {
+			return Filter{}, fmt.Errorf("compiling suite pattern %q: %w", suitePattern, err)
+		}
+		testRE, err := regexp.Compile(testPattern)
+		if err != nil {
+			return Filter{}, fmt.Errorf("compiling test pattern %q: %w", testPattern, err)
+		}
+		return Filter{suiteRE: suiteRE, testRE: testRE}, nil
+	}
+
+	bareRE, err := regexp.Compile(run)
+	if err != nil {
+		return Filter{}, fmt.Errorf("compiling pattern %q: %w", run, err)
+	}
+	return Filter{bareRE: bareRE}, nil
+}
+
+// MatchesSuite reports whether any test in s could match f. It's a cheap
+// pre-check so callers can skip suites that have no chance of contributing
+// a result before reading/evaluating them.
+func (f Filter) MatchesSuite(s Suite) bool {
+	switch {
+	case f.suiteRE != nil:
+		return f.suiteRE.MatchString(s.Name)
+	case f.bareRE != nil:
+		if f.bareRE.MatchString(s.Name) {
+			return true
+		}
+		for _, test := range s.Tests {
+			for _, c := range test.Cases {
+				if matchesTestName(f.bareRE, test, c) {
+					return true
+				}
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// MatchesTest reports whether f selects case c of test, within suite s.
+func (f Filter) MatchesTest(s Suite, test SuiteTest, c Case) bool {
+	switch {
+	case f.suiteRE != nil:
+		return f.suiteRE.MatchString(s.Name) && matchesTestName(f.testRE, test, c)
+	case f.bareRE != nil:
+		return f.bareRE.MatchString(s.Name) || matchesTestName(f.bareRE, test, c)
+	default:
+		return true
+	}
+}
+
+// matchesTestName reports whether re matches either the Test's own name,
+// so that e.g. "^nginx-deployment$" selects every Case of that Test, or
+// the qualified name of Case c within it.
+func matchesTestName(re *regexp.Regexp, test SuiteTest, c Case) bool {
+	return re.MatchString(test.Name) || re.MatchString(testName(test, c))
+}
+
+// testName is the qualified name of Case c within test: the Test's name
+// and the Case's name, joined the same way Result.TestName is.
+func testName(test SuiteTest, c Case) string {
+	return test.Name + "/" + c.Name
+}
@@ -0,0 +1,29 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+)
+
+// humanReporter reproduces gator test's original free-form output: one
+// line per test result.
+type humanReporter struct{}
+
+func (r *humanReporter) Report(w io.Writer, suites []SuiteResult) error {
+	for _, suite := range suites {
+		if err := r.ReportSuite(w, suite); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReportSuite implements SuiteReporter.
+func (r *humanReporter) ReportSuite(w io.Writer, suite SuiteResult) error {
+	for _, result := range suite.Results {
+		if _, err := fmt.Fprintln(w, result.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
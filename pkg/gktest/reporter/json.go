@@ -0,0 +1,52 @@
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonTest is the JSON-serializable form of a single test's result.
+type jsonTest struct {
+	Name      string `json:"name"`
+	Pass      bool   `json:"pass"`
+	ElapsedNS int64  `json:"elapsedNs"`
+	Error     string `json:"error,omitempty"`
+}
+
+// jsonSuite is the JSON-serializable form of a suite's results. One
+// jsonSuite object is streamed per suite so that consumers can process
+// results incrementally rather than buffering the entire run.
+type jsonSuite struct {
+	Suite string     `json:"suite"`
+	Tests []jsonTest `json:"tests"`
+}
+
+// jsonReporter streams one JSON object per suite to w, newline-delimited.
+type jsonReporter struct{}
+
+func (r *jsonReporter) Report(w io.Writer, suites []SuiteResult) error {
+	for _, suite := range suites {
+		if err := r.ReportSuite(w, suite); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReportSuite implements SuiteReporter.
+func (r *jsonReporter) ReportSuite(w io.Writer, suite SuiteResult) error {
+	out := jsonSuite{Suite: suite.Path}
+	for _, result := range suite.Results {
+		test := jsonTest{
+			Name:      result.TestName,
+			Pass:      !result.IsFailure(),
+			ElapsedNS: result.Elapsed.Nanoseconds(),
+		}
+		if result.Error != nil {
+			test.Error = result.Error.Error()
+		}
+		out.Tests = append(out.Tests, test)
+	}
+
+	return json.NewEncoder(w).Encode(out)
+}
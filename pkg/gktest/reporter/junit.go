@@ -0,0 +1,74 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// The junitTestSuites/junitTestSuite/junitTestCase types model just enough
+// of the JUnit XML schema for CI dashboards (e.g. the KIND-based e2e
+// pipelines other Kubernetes subprojects report to) to parse pass/fail
+// counts and per-test timing and failure messages.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name    string        `xml:"name,attr"`
+	Time    float64       `xml:"time,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitReporter aggregates all suites into a single JUnit XML document.
+type junitReporter struct{}
+
+func (r *junitReporter) Report(w io.Writer, suites []SuiteResult) error {
+	doc := junitTestSuites{}
+
+	for _, suite := range suites {
+		jsuite := junitTestSuite{Name: suite.Path}
+
+		for _, result := range suite.Results {
+			jcase := junitTestCase{
+				Name: result.TestName,
+				Time: result.Elapsed.Seconds(),
+			}
+			if result.Error != nil {
+				jsuite.Failures++
+				jcase.Failure = &junitFailure{
+					Message: result.Error.Error(),
+					Text:    result.Error.Error(),
+				}
+			}
+			jsuite.Tests++
+			jsuite.Cases = append(jsuite.Cases, jcase)
+		}
+
+		doc.Suites = append(doc.Suites, jsuite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
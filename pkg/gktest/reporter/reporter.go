@@ -0,0 +1,50 @@
+// Package reporter renders gator test results in the output formats
+// consumed by `gator test --output`: a human-readable format for
+// interactive use, and json/junit formats for CI pipelines.
+package reporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/open-policy-agent/gatekeeper/pkg/gktest"
+)
+
+// SuiteResult is the set of test Results produced by running a single
+// Suite, along with where that Suite came from.
+type SuiteResult struct {
+	// Path is the file path the Suite was read from.
+	Path string
+	// Results are the outcomes of the Suite's tests.
+	Results []gktest.Result
+}
+
+// Reporter writes a set of SuiteResults to w in a specific format.
+type Reporter interface {
+	Report(w io.Writer, suites []SuiteResult) error
+}
+
+// SuiteReporter is implemented by Reporters that can emit a single suite's
+// results as soon as that suite finishes running, instead of waiting to
+// see the whole run. human and json output support this, for the immediate
+// feedback `gator test` has always given on a large tree or under
+// --watch. junit cannot: it needs every suite in hand to emit one
+// well-formed document, so it implements only Reporter.
+type SuiteReporter interface {
+	ReportSuite(w io.Writer, suite SuiteResult) error
+}
+
+// New returns the Reporter for the named format. format must be one of
+// "human", "json", or "junit".
+func New(format string) (Reporter, error) {
+	switch format {
+	case "", "human":
+		return &humanReporter{}, nil
+	case "json":
+		return &jsonReporter{}, nil
+	case "junit":
+		return &junitReporter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q: must be \"human\", \"json\", or \"junit\"", format)
+	}
+}
@@ -0,0 +1,134 @@
+package reporter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-policy-agent/gatekeeper/pkg/gktest"
+)
+
+func testSuites() []SuiteResult {
+	return []SuiteResult{
+		{
+			Path: "tests/forbid-labels.yaml",
+			Results: []gktest.Result{
+				{SuitePath: "tests/forbid-labels.yaml", TestName: "allowed", Elapsed: 1500 * time.Microsecond},
+				{SuitePath: "tests/forbid-labels.yaml", TestName: "denied", Error: errors.New("expected denial, object was allowed"), Elapsed: 2500 * time.Microsecond},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		format  string
+		want    interface{}
+		wantErr bool
+	}{
+		{format: "", want: &humanReporter{}},
+		{format: "human", want: &humanReporter{}},
+		{format: "json", want: &jsonReporter{}},
+		{format: "junit", want: &junitReporter{}},
+		{format: "xml", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.format, func(t *testing.T) {
+			got, err := New(tc.format)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, wantErr %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected Reporter: %s", diff)
+			}
+		})
+	}
+}
+
+func TestHumanReporter_Report(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&humanReporter{}).Report(&buf, testSuites()); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+
+	want := "PASS: tests/forbid-labels.yaml/allowed\n" +
+		"FAIL: tests/forbid-labels.yaml/denied: expected denial, object was allowed\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("unexpected output: %s", diff)
+	}
+}
+
+func TestHumanReporter_ReportSuite(t *testing.T) {
+	var buf bytes.Buffer
+	r := &humanReporter{}
+	for _, suite := range testSuites() {
+		if err := r.ReportSuite(&buf, suite); err != nil {
+			t.Fatalf("ReportSuite: %v", err)
+		}
+	}
+
+	want := "PASS: tests/forbid-labels.yaml/allowed\n" +
+		"FAIL: tests/forbid-labels.yaml/denied: expected denial, object was allowed\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("unexpected output: %s", diff)
+	}
+
+	// Report must produce exactly the concatenation of ReportSuite calls -
+	// one format, two code paths.
+	var viaReport bytes.Buffer
+	if err := (&humanReporter{}).Report(&viaReport, testSuites()); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if diff := cmp.Diff(viaReport.String(), buf.String()); diff != "" {
+		t.Errorf("Report and ReportSuite disagree: %s", diff)
+	}
+}
+
+func TestJSONReporter_Report(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&jsonReporter{}).Report(&buf, testSuites()); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+
+	want := `{"suite":"tests/forbid-labels.yaml","tests":[` +
+		`{"name":"allowed","pass":true,"elapsedNs":1500000},` +
+		`{"name":"denied","pass":false,"elapsedNs":2500000,"error":"expected denial, object was allowed"}` +
+		"]}\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("unexpected output: %s", diff)
+	}
+}
+
+func TestJUnitReporter_Report(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&junitReporter{}).Report(&buf, testSuites()); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, `<?xml version="1.0" encoding="UTF-8"?>`+"\n") {
+		t.Fatalf("missing XML header: %s", out)
+	}
+	if !strings.HasSuffix(out, "\n") {
+		t.Fatalf("output does not end with a trailing newline: %q", out)
+	}
+
+	wantFields := []string{
+		`<testsuite name="tests/forbid-labels.yaml" tests="2" failures="1">`,
+		`<testcase name="allowed" time="0.0015"></testcase>`,
+		`<testcase name="denied" time="0.0025">`,
+		`<failure message="expected denial, object was allowed">expected denial, object was allowed</failure>`,
+	}
+	for _, want := range wantFields {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package gktest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/open-policy-agent/frameworks/constraint/pkg/types"
+)
+
+// Result is the outcome of running a single test within a suite: whether
+// the object was handled as the test expected, and - if not - why.
+type Result struct {
+	// SuitePath is the path to the Suite file this Result belongs to.
+	SuitePath string
+	// TestName is the name of the Test and Case this Result belongs to,
+	// joined with "/", e.g. "must-have-owner/allowed".
+	TestName string
+	// Error is set if the test failed, either because the object was
+	// allowed/denied when the test expected the opposite, or because
+	// evaluating the object against the template/constraint itself failed.
+	Error error
+	// Elapsed is how long the test took to evaluate, excluding suite-level
+	// setup shared across tests.
+	Elapsed time.Duration
+}
+
+// IsFailure reports whether the test did not pass.
+func (r Result) IsFailure() bool {
+	return r.Error != nil
+}
+
+// String renders the Result the way it is printed to stdout by `gator
+// test`'s human-readable output.
+func (r Result) String() string {
+	if r.IsFailure() {
+		return fmt.Sprintf("FAIL: %s/%s: %v", r.SuitePath, r.TestName, r.Error)
+	}
+	return fmt.Sprintf("PASS: %s/%s", r.SuitePath, r.TestName)
+}
+
+// resultFromReview converts a constraint framework review response into a
+// Result. It does not set SuitePath/TestName; callers fill those in once
+// the Result is attributed to a specific test.
+func resultFromReview(review *types.Responses) *Result {
+	if review == nil {
+		return &Result{}
+	}
+
+	var messages []string
+	for _, res := range review.Results() {
+		if res == nil {
+			continue
+		}
+		messages = append(messages, res.Msg)
+	}
+	if len(messages) == 0 {
+		return &Result{}
+	}
+
+	return &Result{Error: fmt.Errorf("%s", strings.Join(messages, "; "))}
+}
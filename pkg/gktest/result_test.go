@@ -0,0 +1,61 @@
+package gktest
+
+import (
+	"testing"
+
+	"github.com/open-policy-agent/frameworks/constraint/pkg/types"
+)
+
+// newResponses builds a *types.Responses carrying results as if a single
+// target had reported them, which is all resultFromReview cares about.
+func newResponses(results ...*types.Result) *types.Responses {
+	r := types.NewResponses()
+	if len(results) > 0 {
+		r.ByTarget["k8s"] = &types.Response{Results: results}
+	}
+	return r
+}
+
+func TestResultFromReview(t *testing.T) {
+	tests := []struct {
+		name      string
+		review    *types.Responses
+		wantError string
+	}{
+		{name: "nil review passes", review: nil},
+		{name: "no results passes", review: types.NewResponses()},
+		{
+			name:      "single violation fails",
+			review:    newResponses(&types.Result{Msg: "missing owner label"}),
+			wantError: "missing owner label",
+		},
+		{
+			name: "every violation is reported, not just the first",
+			review: newResponses(
+				&types.Result{Msg: "missing owner label"},
+				&types.Result{Msg: "missing cost-center label"},
+			),
+			wantError: "missing owner label; missing cost-center label",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := resultFromReview(tc.review)
+
+			if tc.wantError == "" {
+				if got.IsFailure() {
+					t.Fatalf("got failure %v, want a passing Result", got.Error)
+				}
+				return
+			}
+
+			if !got.IsFailure() {
+				t.Fatal("got a passing Result, want a failure")
+			}
+			if got.Error.Error() != tc.wantError {
+				t.Errorf("got error %q, want %q", got.Error.Error(), tc.wantError)
+			}
+		})
+	}
+}
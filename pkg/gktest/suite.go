@@ -0,0 +1,260 @@
+package gktest
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/open-policy-agent/frameworks/constraint/pkg/apis/templates/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// suiteKind is the `kind:` a YAML file must declare for ReadSuites to treat
+// it as a test suite rather than one of the template/constraint/object
+// files a suite's Tests reference.
+const suiteKind = "Suite"
+
+// Suite is a single `gator test` suite file: a named group of Tests, each
+// pairing a ConstraintTemplate/Constraint with the object fixtures (Cases)
+// it's expected to allow or deny.
+type Suite struct {
+	// path is the file Suite was read from, relative to the fs.FS
+	// ReadSuites was called with.
+	path string
+
+	Name  string      `json:"name"`
+	Tests []SuiteTest `json:"tests"`
+}
+
+// Path returns the file Suite was read from.
+func (s Suite) Path() string {
+	return s.path
+}
+
+// SuiteTest pairs a ConstraintTemplate/Constraint with the object fixtures
+// it's evaluated against. TemplateFile/ConstraintFile are resolved by
+// ReadSuites to paths relative to the same fs.FS the Suite itself was read
+// from, so Suite.Run can load them lazily from whichever filesystem its
+// caller passes in.
+type SuiteTest struct {
+	Name string `json:"name"`
+
+	TemplateFile   string `json:"template"`
+	ConstraintFile string `json:"constraint"`
+
+	Cases []Case `json:"cases"`
+}
+
+// Case is a single object fixture and what it's expected to do when
+// reviewed against its Test's ConstraintTemplate/Constraint.
+type Case struct {
+	Name       string     `json:"name"`
+	ObjectFile string     `json:"object"`
+	Assertions Assertions `json:"assertions"`
+}
+
+// Assertions is what a Case expects its review to find.
+type Assertions struct {
+	// Violations is whether the object is expected to violate the
+	// Constraint under test.
+	Violations bool `json:"violations"`
+}
+
+// ReadSuites finds every Suite file at or under path and parses it,
+// resolving each Test's Template/Constraint/Object file references
+// relative to the directory the Suite file lives in. path may itself be a
+// single suite file.
+func ReadSuites(fileSystem fs.FS, path string) ([]Suite, error) {
+	paths, err := suitePaths(fileSystem, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var suites []Suite
+	for _, p := range paths {
+		suite, err := readSuite(fileSystem, p)
+		if err != nil {
+			return nil, fmt.Errorf("reading suite %s: %w", p, err)
+		}
+		if suite == nil {
+			// A YAML file under path that isn't itself a Suite - one of the
+			// template/constraint/object fixtures a Suite references.
+			continue
+		}
+		suites = append(suites, *suite)
+	}
+
+	return suites, nil
+}
+
+// suitePaths returns every YAML file at or under p, relative to fileSystem.
+func suitePaths(fileSystem fs.FS, p string) ([]string, error) {
+	rel := relPath(p)
+
+	info, err := fs.Stat(fileSystem, rel)
+	if err != nil {
+		return nil, fmt.Errorf("stat %s: %w", p, err)
+	}
+	if !info.IsDir() {
+		return []string{rel}, nil
+	}
+
+	var paths []string
+	err = fs.WalkDir(fileSystem, rel, func(walked string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(path.Ext(walked)) {
+		case ".yaml", ".yml":
+			paths = append(paths, walked)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking %s: %w", p, err)
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// readSuite parses the YAML file at p. It returns a nil Suite and nil
+// error for a YAML file that isn't a Suite (kind != "Suite"), since
+// ReadSuites walks over a suite's referenced fixtures too and those aren't
+// an error.
+func readSuite(fileSystem fs.FS, p string) (*Suite, error) {
+	data, err := fs.ReadFile(fileSystem, p)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeMeta struct {
+		Kind string `json:"kind"`
+	}
+	if err := yaml.Unmarshal(data, &typeMeta); err != nil {
+		return nil, fmt.Errorf("parsing: %w", err)
+	}
+	if typeMeta.Kind != suiteKind {
+		return nil, nil
+	}
+
+	var suite Suite
+	if err := yaml.Unmarshal(data, &suite); err != nil {
+		return nil, fmt.Errorf("parsing: %w", err)
+	}
+	suite.path = p
+
+	dir := path.Dir(p)
+	for i := range suite.Tests {
+		test := &suite.Tests[i]
+		test.TemplateFile = path.Join(dir, test.TemplateFile)
+		test.ConstraintFile = path.Join(dir, test.ConstraintFile)
+		for j := range test.Cases {
+			test.Cases[j].ObjectFile = path.Join(dir, test.Cases[j].ObjectFile)
+		}
+	}
+
+	return &suite, nil
+}
+
+// relPath converts an absolute path into one relative to an fs.FS rooted
+// at "/", the convention getFS/osFS use everywhere a Suite path is passed
+// around in this package's callers.
+func relPath(p string) string {
+	rel := strings.TrimPrefix(p, "/")
+	if rel == "" {
+		return "."
+	}
+	return rel
+}
+
+// Run evaluates every Case in every Test of this Suite that filter
+// selects, reviewing each object fixture against its Test's
+// ConstraintTemplate/Constraint via evaluator. A Case whose fixtures can't
+// be loaded, or whose review otherwise fails to execute, is reported as a
+// failing Result rather than aborting the run - one broken test shouldn't
+// hide the outcome of the rest of the suite.
+func (s Suite) Run(fileSystem fs.FS, filter Filter, evaluator Evaluator) []Result {
+	var results []Result
+
+	for _, test := range s.Tests {
+		for _, c := range test.Cases {
+			if !filter.MatchesTest(s, test, c) {
+				continue
+			}
+
+			results = append(results, s.runCase(fileSystem, test, c, evaluator))
+		}
+	}
+
+	return results
+}
+
+// runCase loads test/c's fixtures, reviews the object through evaluator,
+// and checks the outcome against c's Assertions.
+func (s Suite) runCase(fileSystem fs.FS, test SuiteTest, c Case, evaluator Evaluator) Result {
+	start := time.Now()
+
+	result := Result{SuitePath: s.path, TestName: test.Name + "/" + c.Name}
+
+	template := &v1beta1.ConstraintTemplate{}
+	if err := readFixture(fileSystem, test.TemplateFile, template); err != nil {
+		result.Error = fmt.Errorf("reading template %s: %w", test.TemplateFile, err)
+		result.Elapsed = time.Since(start)
+		return result
+	}
+
+	constraint := &unstructured.Unstructured{}
+	if err := readFixture(fileSystem, test.ConstraintFile, constraint); err != nil {
+		result.Error = fmt.Errorf("reading constraint %s: %w", test.ConstraintFile, err)
+		result.Elapsed = time.Since(start)
+		return result
+	}
+
+	object := &unstructured.Unstructured{}
+	if err := readFixture(fileSystem, c.ObjectFile, object); err != nil {
+		result.Error = fmt.Errorf("reading object %s: %w", c.ObjectFile, err)
+		result.Elapsed = time.Since(start)
+		return result
+	}
+
+	review, err := evaluator.NewReview(context.Background(), template, constraint, object)
+	result.Elapsed = time.Since(start)
+	if err != nil {
+		result.Error = fmt.Errorf("reviewing object: %w", err)
+		return result
+	}
+
+	result.Error = checkAssertions(c.Assertions, review)
+	return result
+}
+
+// readFixture reads the YAML/JSON file at p, relative to fileSystem, and
+// unmarshals it into v.
+func readFixture(fileSystem fs.FS, p string, v interface{}) error {
+	data, err := fs.ReadFile(fileSystem, relPath(p))
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, v)
+}
+
+// checkAssertions compares a Case's expectations against the Result its
+// object's review actually produced.
+func checkAssertions(want Assertions, got *Result) error {
+	if want.Violations == got.IsFailure() {
+		return nil
+	}
+	if want.Violations {
+		return fmt.Errorf("expected a violation, object was allowed")
+	}
+	return fmt.Errorf("expected no violation, object was denied: %v", got.Error)
+}
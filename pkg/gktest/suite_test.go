@@ -0,0 +1,135 @@
+package gktest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/open-policy-agent/frameworks/constraint/pkg/apis/templates/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const templateYAML = `kind: ConstraintTemplate
+metadata:
+  name: k8srequiredlabels
+`
+
+const constraintYAML = `kind: K8sRequiredLabels
+metadata:
+  name: must-have-owner
+`
+
+const allowedObjectYAML = `kind: Pod
+metadata:
+  name: allowed
+`
+
+const deniedObjectYAML = `kind: Pod
+metadata:
+  name: denied
+`
+
+const suiteYAML = `kind: Suite
+name: forbid-labels
+tests:
+- name: must-have-owner
+  template: template.yaml
+  constraint: constraint.yaml
+  cases:
+  - name: allowed
+    object: allowed.yaml
+    assertions:
+      violations: false
+  - name: denied
+    object: denied.yaml
+    assertions:
+      violations: true
+`
+
+// fakeEvaluator denies any object named "denied" and allows everything
+// else, without compiling or calling out to anything.
+type fakeEvaluator struct{}
+
+func (fakeEvaluator) NewReview(_ context.Context, _ *v1beta1.ConstraintTemplate, _ *unstructured.Unstructured, object *unstructured.Unstructured) (*Result, error) {
+	if object.GetName() == "denied" {
+		return &Result{Error: errors.New("denied by fake evaluator")}, nil
+	}
+	return &Result{}, nil
+}
+
+func (fakeEvaluator) Close() error { return nil }
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"home/user/tests/suite.yaml":      {Data: []byte(suiteYAML)},
+		"home/user/tests/template.yaml":   {Data: []byte(templateYAML)},
+		"home/user/tests/constraint.yaml": {Data: []byte(constraintYAML)},
+		"home/user/tests/allowed.yaml":    {Data: []byte(allowedObjectYAML)},
+		"home/user/tests/denied.yaml":     {Data: []byte(deniedObjectYAML)},
+	}
+}
+
+func TestReadSuites(t *testing.T) {
+	suites, err := ReadSuites(testFS(), "/home/user/tests")
+	if err != nil {
+		t.Fatalf("ReadSuites: %v", err)
+	}
+	if len(suites) != 1 {
+		t.Fatalf("got %d suites, want 1", len(suites))
+	}
+
+	s := suites[0]
+	if s.Name != "forbid-labels" {
+		t.Errorf("got suite name %q, want forbid-labels", s.Name)
+	}
+	if s.Path() != "home/user/tests/suite.yaml" {
+		t.Errorf("got suite path %q, want home/user/tests/suite.yaml", s.Path())
+	}
+	if len(s.Tests) != 1 || len(s.Tests[0].Cases) != 2 {
+		t.Fatalf("unexpected suite structure: %+v", s)
+	}
+	if want := "home/user/tests/template.yaml"; s.Tests[0].TemplateFile != want {
+		t.Errorf("got template file %q, want %q", s.Tests[0].TemplateFile, want)
+	}
+}
+
+func TestSuite_Run(t *testing.T) {
+	suites, err := ReadSuites(testFS(), "/home/user/tests")
+	if err != nil {
+		t.Fatalf("ReadSuites: %v", err)
+	}
+	s := suites[0]
+
+	results := s.Run(testFS(), Filter{}, fakeEvaluator{})
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+
+	for _, r := range results {
+		if r.IsFailure() {
+			t.Errorf("unexpected failing result %q: %v", r.TestName, r.Error)
+		}
+	}
+}
+
+func TestSuite_Run_filtered(t *testing.T) {
+	suites, err := ReadSuites(testFS(), "/home/user/tests")
+	if err != nil {
+		t.Fatalf("ReadSuites: %v", err)
+	}
+	s := suites[0]
+
+	filter, err := NewFilter("//allowed")
+	if err != nil {
+		t.Fatalf("NewFilter: %v", err)
+	}
+
+	results := s.Run(testFS(), filter, fakeEvaluator{})
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	if results[0].TestName != "must-have-owner/allowed" {
+		t.Errorf("got test name %q, want must-have-owner/allowed", results[0].TestName)
+	}
+}
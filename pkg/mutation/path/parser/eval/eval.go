@@ -0,0 +1,351 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eval compiles a parser.Path into a visitor that reads, writes,
+// and deletes values in a map[string]interface{}/[]interface{} tree - the
+// shape unstructured.Unstructured.Object already has. This lets mutation
+// webhooks and assign-style policies act on the same path DSL the parser
+// package defines for addressing fields, rather than re-implementing
+// field traversal on top of it.
+package eval
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/open-policy-agent/gatekeeper/pkg/mutation/path/parser"
+)
+
+// ErrPathMismatch is returned when a Path node does not match the shape
+// of the value it is being evaluated against - for example an Object node
+// addressing a value that isn't a map, or a List node addressing a value
+// that isn't a slice.
+var ErrPathMismatch = errors.New("path does not match object structure")
+
+// ErrAmbiguousTarget is returned by Set when path contains a Glob list
+// selector or an index range. Writing through either would have to pick
+// one of potentially many matching elements, which Set refuses to do
+// implicitly.
+var ErrAmbiguousTarget = errors.New("cannot Set through a selector matching more than one element")
+
+// ErrIndexOutOfRange is returned by Set when an Index selector addresses
+// a position that isn't either an existing element or the next one to
+// append.
+var ErrIndexOutOfRange = errors.New("list index out of range")
+
+// Get returns every value addressed by path under root. A path with no
+// List selectors matches at most one value; each Glob selector it passes
+// through can multiply the number of matches.
+func Get(root map[string]interface{}, path parser.Path) ([]interface{}, error) {
+	current := []interface{}{interface{}(root)}
+
+	for _, node := range path.Nodes {
+		var next []interface{}
+
+		switch n := node.(type) {
+		case *parser.Object:
+			for _, v := range current {
+				m, ok := v.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("%w: expected object at %q, got %T", ErrPathMismatch, n.Reference, v)
+				}
+				if child, found := m[n.Reference]; found {
+					next = append(next, child)
+				}
+			}
+
+		case *parser.List:
+			for _, v := range current {
+				list, ok := v.([]interface{})
+				if !ok {
+					return nil, fmt.Errorf("%w: expected list at [%s], got %T", ErrPathMismatch, n, v)
+				}
+
+				if n.Index != nil {
+					start, end, inBounds := indexRange(n, len(list))
+					if inBounds {
+						next = append(next, list[start:end+1]...)
+					}
+					continue
+				}
+
+				for _, elem := range list {
+					if matchesListElement(n, elem) {
+						next = append(next, elem)
+					}
+				}
+			}
+
+		default:
+			return nil, fmt.Errorf("%w: unknown node type %T", ErrPathMismatch, node)
+		}
+
+		current = next
+	}
+
+	return current, nil
+}
+
+// Set writes value at the single location path addresses under root,
+// creating intermediate maps and list elements as needed. path may not
+// contain a Glob list selector, since there would be no single, obvious
+// element to create or overwrite.
+func Set(root map[string]interface{}, path parser.Path, value interface{}) error {
+	if len(path.Nodes) == 0 {
+		return fmt.Errorf("%w: path must reference at least one field", ErrPathMismatch)
+	}
+
+	_, err := setRec(root, path.Nodes, value)
+	return err
+}
+
+// setRec applies nodes to current, creating missing maps/list elements
+// along the way, and returns the (possibly newly created) value that
+// should replace current in its parent container.
+func setRec(current interface{}, nodes []parser.Node, value interface{}) (interface{}, error) {
+	if len(nodes) == 0 {
+		return value, nil
+	}
+
+	switch n := nodes[0].(type) {
+	case *parser.Object:
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			if current != nil {
+				return nil, fmt.Errorf("%w: expected object at %q, got %T", ErrPathMismatch, n.Reference, current)
+			}
+			m = map[string]interface{}{}
+		}
+
+		newChild, err := setRec(m[n.Reference], nodes[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		m[n.Reference] = newChild
+		return m, nil
+
+	case *parser.List:
+		var list []interface{}
+		if current != nil {
+			existing, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%w: expected list at [%s], got %T", ErrPathMismatch, n, current)
+			}
+			list = existing
+		}
+
+		if n.Index != nil {
+			return setRecIndex(n, list, nodes[1:], value)
+		}
+
+		if n.Glob {
+			return nil, fmt.Errorf("%w: selector %s", ErrAmbiguousTarget, n)
+		}
+		if n.KeyValue == nil {
+			return nil, fmt.Errorf("%w: List selector missing a KeyValue", ErrPathMismatch)
+		}
+
+		for i, elem := range list {
+			if !matchesListElement(n, elem) {
+				continue
+			}
+			newElem, err := setRec(elem, nodes[1:], value)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = newElem
+			return list, nil
+		}
+
+		// No existing element matched; create one carrying the selector's
+		// key/value so that a later Get against the same path finds it.
+		newElem, err := setRec(map[string]interface{}{n.KeyField: *n.KeyValue}, nodes[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		return append(list, newElem), nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown node type %T", ErrPathMismatch, nodes[0])
+	}
+}
+
+// setRecIndex applies an Index selector's remaining nodes to list,
+// overwriting the element at n.Index or, if n.Index is exactly one past
+// the end of list, appending a new one. n.IndexEnd is rejected: a range
+// addresses more than one element, and Set refuses to guess which one(s)
+// the caller meant.
+func setRecIndex(n *parser.List, list []interface{}, rest []parser.Node, value interface{}) (interface{}, error) {
+	if n.IndexEnd != nil {
+		return nil, fmt.Errorf("%w: selector %s", ErrAmbiguousTarget, n)
+	}
+
+	idx := *n.Index
+	switch {
+	case idx < 0:
+		return nil, fmt.Errorf("%w: %d", ErrIndexOutOfRange, idx)
+	case idx < len(list):
+		newElem, err := setRec(list[idx], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		list[idx] = newElem
+		return list, nil
+	case idx == len(list):
+		newElem, err := setRec(nil, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		return append(list, newElem), nil
+	default:
+		return nil, fmt.Errorf("%w: index %d, list has %d element(s)", ErrIndexOutOfRange, idx, len(list))
+	}
+}
+
+// Delete removes the value(s) addressed by path from root. Unlike Set,
+// Glob selectors are allowed: every matching element is deleted. Deleting
+// a path that doesn't exist is a no-op, not an error.
+func Delete(root map[string]interface{}, path parser.Path) error {
+	if len(path.Nodes) == 0 {
+		return fmt.Errorf("%w: path must reference at least one field", ErrPathMismatch)
+	}
+
+	_, err := deleteRec(root, path.Nodes)
+	return err
+}
+
+// deleteRec applies nodes to current, removing whatever they address, and
+// returns the value that should replace current in its parent container.
+func deleteRec(current interface{}, nodes []parser.Node) (interface{}, error) {
+	if current == nil {
+		return nil, nil
+	}
+
+	switch n := nodes[0].(type) {
+	case *parser.Object:
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: expected object at %q, got %T", ErrPathMismatch, n.Reference, current)
+		}
+
+		child, found := m[n.Reference]
+		if !found {
+			return m, nil
+		}
+
+		if len(nodes) == 1 {
+			delete(m, n.Reference)
+			return m, nil
+		}
+
+		newChild, err := deleteRec(child, nodes[1:])
+		if err != nil {
+			return nil, err
+		}
+		m[n.Reference] = newChild
+		return m, nil
+
+	case *parser.List:
+		list, ok := current.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: expected list at [%s], got %T", ErrPathMismatch, n, current)
+		}
+
+		if n.Index != nil {
+			return deleteRecIndex(n, list, nodes[1:])
+		}
+
+		if len(nodes) == 1 {
+			kept := list[:0:0]
+			for _, elem := range list {
+				if !matchesListElement(n, elem) {
+					kept = append(kept, elem)
+				}
+			}
+			return kept, nil
+		}
+
+		for i, elem := range list {
+			if !matchesListElement(n, elem) {
+				continue
+			}
+			newElem, err := deleteRec(elem, nodes[1:])
+			if err != nil {
+				return nil, err
+			}
+			list[i] = newElem
+		}
+		return list, nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown node type %T", ErrPathMismatch, nodes[0])
+	}
+}
+
+// deleteRecIndex applies an Index or IndexEnd selector's remaining nodes to
+// list. An out-of-range index/range is a no-op, consistent with Delete's
+// "missing path" semantics elsewhere.
+func deleteRecIndex(n *parser.List, list []interface{}, rest []parser.Node) (interface{}, error) {
+	start, end, inBounds := indexRange(n, len(list))
+	if !inBounds {
+		return list, nil
+	}
+
+	if len(rest) == 0 {
+		return append(list[:start:start], list[end+1:]...), nil
+	}
+
+	for i := start; i <= end; i++ {
+		newElem, err := deleteRec(list[i], rest)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = newElem
+	}
+	return list, nil
+}
+
+// indexRange resolves n's Index/IndexEnd pair against a list of the given
+// length, returning the inclusive [start, end] slice bounds it addresses.
+// ok is false if the selector falls outside the list, in which case start
+// and end are meaningless.
+func indexRange(n *parser.List, length int) (start, end int, ok bool) {
+	start = *n.Index
+	end = start
+	if n.IndexEnd != nil {
+		end = *n.IndexEnd
+	}
+
+	if start < 0 || end < start || start >= length || end >= length {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// matchesListElement reports whether elem is selected by n: every element
+// if n is a Glob, otherwise only elements whose KeyField equals KeyValue.
+func matchesListElement(n *parser.List, elem interface{}) bool {
+	if n.Glob {
+		return true
+	}
+
+	em, ok := elem.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	s, ok := em[n.KeyField].(string)
+	return ok && n.KeyValue != nil && s == *n.KeyValue
+}
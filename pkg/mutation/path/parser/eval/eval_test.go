@@ -0,0 +1,336 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eval
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-policy-agent/gatekeeper/pkg/mutation/path/parser"
+)
+
+func mustParse(t *testing.T, input string) parser.Path {
+	t.Helper()
+	p, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("parsing %q: %v", input, err)
+	}
+	return p
+}
+
+func TestGet(t *testing.T) {
+	object := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "a", "image": "a:1"},
+				map[string]interface{}{"name": "b", "image": "b:1"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    []interface{}
+		wantErr error
+	}{
+		{
+			name: "single field",
+			path: `spec.containers[name: "a"].image`,
+			want: []interface{}{"a:1"},
+		},
+		{
+			name: "glob matches every element",
+			path: `spec.containers[name: *].image`,
+			want: []interface{}{"a:1", "b:1"},
+		},
+		{
+			name: "missing key returns no matches",
+			path: `spec.containers[name: "c"].image`,
+			want: nil,
+		},
+		{
+			name: "missing field returns no matches",
+			path: `spec.replicas`,
+			want: nil,
+		},
+		{
+			name:    "object node against a list errors",
+			path:    `spec.containers.name`,
+			wantErr: ErrPathMismatch,
+		},
+		{
+			name: "index selects a single element",
+			path: `spec.containers[0].image`,
+			want: []interface{}{"a:1"},
+		},
+		{
+			name: "index range selects a slice of elements",
+			path: `spec.containers[0:1].name`,
+			want: []interface{}{"a", "b"},
+		},
+		{
+			name: "out of range index returns no matches",
+			path: `spec.containers[5].image`,
+			want: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Get(object, mustParse(t, tc.path))
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected result: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	t.Run("overwrites an existing value", func(t *testing.T) {
+		object := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "a", "image": "a:1"},
+				},
+			},
+		}
+
+		if err := Set(object, mustParse(t, `spec.containers[name: "a"].image`), "a:2"); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+
+		got, err := Get(object, mustParse(t, `spec.containers[name: "a"].image`))
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if diff := cmp.Diff([]interface{}{"a:2"}, got); diff != "" {
+			t.Errorf("unexpected result: %s", diff)
+		}
+	})
+
+	t.Run("creates missing intermediate maps and list elements", func(t *testing.T) {
+		object := map[string]interface{}{}
+
+		if err := Set(object, mustParse(t, `spec.containers[name: "a"].image`), "a:1"); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+
+		got, err := Get(object, mustParse(t, `spec.containers[name: "a"].image`))
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if diff := cmp.Diff([]interface{}{"a:1"}, got); diff != "" {
+			t.Errorf("unexpected result: %s", diff)
+		}
+	})
+
+	t.Run("rejects a Glob target", func(t *testing.T) {
+		object := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "a", "image": "a:1"},
+				},
+			},
+		}
+
+		err := Set(object, mustParse(t, `spec.containers[name: *].image`), "a:2")
+		if !errors.Is(err, ErrAmbiguousTarget) {
+			t.Fatalf("got error %v, want %v", err, ErrAmbiguousTarget)
+		}
+	})
+
+	t.Run("index overwrites an existing element", func(t *testing.T) {
+		object := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "a", "image": "a:1"},
+				},
+			},
+		}
+
+		if err := Set(object, mustParse(t, `spec.containers[0].image`), "a:2"); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+
+		got, err := Get(object, mustParse(t, `spec.containers[0].image`))
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if diff := cmp.Diff([]interface{}{"a:2"}, got); diff != "" {
+			t.Errorf("unexpected result: %s", diff)
+		}
+	})
+
+	t.Run("index one past the end appends", func(t *testing.T) {
+		object := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "a", "image": "a:1"},
+				},
+			},
+		}
+
+		if err := Set(object, mustParse(t, `spec.containers[1].image`), "b:1"); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+
+		got, err := Get(object, mustParse(t, `spec.containers[0:1].image`))
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if diff := cmp.Diff([]interface{}{"a:1", "b:1"}, got); diff != "" {
+			t.Errorf("unexpected result: %s", diff)
+		}
+	})
+
+	t.Run("index beyond the end is out of range", func(t *testing.T) {
+		object := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "a", "image": "a:1"},
+				},
+			},
+		}
+
+		err := Set(object, mustParse(t, `spec.containers[5].image`), "c:1")
+		if !errors.Is(err, ErrIndexOutOfRange) {
+			t.Fatalf("got error %v, want %v", err, ErrIndexOutOfRange)
+		}
+	})
+
+	t.Run("rejects an index range target", func(t *testing.T) {
+		object := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "a", "image": "a:1"},
+					map[string]interface{}{"name": "b", "image": "b:1"},
+				},
+			},
+		}
+
+		err := Set(object, mustParse(t, `spec.containers[0:1].image`), "c:1")
+		if !errors.Is(err, ErrAmbiguousTarget) {
+			t.Fatalf("got error %v, want %v", err, ErrAmbiguousTarget)
+		}
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("removes a matching element", func(t *testing.T) {
+		object := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "a", "image": "a:1"},
+					map[string]interface{}{"name": "b", "image": "b:1"},
+				},
+			},
+		}
+
+		if err := Delete(object, mustParse(t, `spec.containers[name: "a"]`)); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		got, err := Get(object, mustParse(t, `spec.containers[name: *].name`))
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if diff := cmp.Diff([]interface{}{"b"}, got); diff != "" {
+			t.Errorf("unexpected result: %s", diff)
+		}
+	})
+
+	t.Run("missing path is a no-op", func(t *testing.T) {
+		object := map[string]interface{}{}
+
+		if err := Delete(object, mustParse(t, `spec.containers[name: "a"]`)); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+	})
+
+	t.Run("index removes a single element", func(t *testing.T) {
+		object := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "a", "image": "a:1"},
+					map[string]interface{}{"name": "b", "image": "b:1"},
+				},
+			},
+		}
+
+		if err := Delete(object, mustParse(t, `spec.containers[0]`)); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		got, err := Get(object, mustParse(t, `spec.containers[name: *].name`))
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if diff := cmp.Diff([]interface{}{"b"}, got); diff != "" {
+			t.Errorf("unexpected result: %s", diff)
+		}
+	})
+
+	t.Run("index range removes every element it spans", func(t *testing.T) {
+		object := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "a", "image": "a:1"},
+					map[string]interface{}{"name": "b", "image": "b:1"},
+					map[string]interface{}{"name": "c", "image": "c:1"},
+				},
+			},
+		}
+
+		if err := Delete(object, mustParse(t, `spec.containers[0:1]`)); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		got, err := Get(object, mustParse(t, `spec.containers[name: *].name`))
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if diff := cmp.Diff([]interface{}{"c"}, got); diff != "" {
+			t.Errorf("unexpected result: %s", diff)
+		}
+	})
+
+	t.Run("out of range index is a no-op", func(t *testing.T) {
+		object := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "a", "image": "a:1"},
+				},
+			},
+		}
+
+		if err := Delete(object, mustParse(t, `spec.containers[5]`)); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		got, err := Get(object, mustParse(t, `spec.containers[name: *].name`))
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if diff := cmp.Diff([]interface{}{"a"}, got); diff != "" {
+			t.Errorf("unexpected result: %s", diff)
+		}
+	})
+}
@@ -0,0 +1,475 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package parser implements the path DSL used to address fields inside a
+// Kubernetes object from mutation policies: a dot-separated sequence of
+// field references, optionally indexing into a list with a
+// `[keyField: keyValue]`, `[keyField: *]`, `[index]` or `[index:indexEnd]`
+// selector - for example `spec.containers[name: *].securityContext` or
+// `spec.containers[0].image`.
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrUnexpectedToken is returned when the input cannot be parsed as a
+// valid Path.
+var ErrUnexpectedToken = errors.New("unexpected token")
+
+// ErrTrailingSeparator is returned when the input ends with a "." with no
+// following field reference.
+var ErrTrailingSeparator = errors.New("trailing separator")
+
+// Node is a single segment of a parsed Path: either a field reference
+// (Object) or a list element selector (List).
+type Node interface {
+	fmt.Stringer
+
+	// DeepCopyNode returns a deep copy of the Node.
+	DeepCopyNode() Node
+}
+
+// Path is a parsed sequence of Nodes.
+type Path struct {
+	Nodes []Node
+}
+
+// String reconstructs the path DSL representation of p. Parsing the
+// result of String always produces an identical Path.
+func (p *Path) String() string {
+	return joinNodes(p.Nodes)
+}
+
+// DeepCopyNode returns a deep copy of p.
+func (p *Path) DeepCopyNode() Node {
+	out := &Path{}
+	if p.Nodes != nil {
+		out.Nodes = make([]Node, len(p.Nodes))
+		for i, n := range p.Nodes {
+			out.Nodes[i] = n.DeepCopyNode()
+		}
+	}
+	return out
+}
+
+// Object is a reference to a named field, e.g. `spec` or `"my field"`.
+type Object struct {
+	// Reference is the field name being referenced.
+	Reference string
+}
+
+// String implements Node.
+func (o *Object) String() string {
+	return quoteIfNeeded(o.Reference)
+}
+
+// DeepCopyNode implements Node.
+func (o *Object) DeepCopyNode() Node {
+	return &Object{Reference: o.Reference}
+}
+
+// List selects one or more elements of a list field, either by comparing
+// KeyField of each element to KeyValue (with Glob matching every element),
+// or by position via Index/IndexEnd. Exactly one of the two selection
+// modes is populated: Index and KeyField/Glob are mutually exclusive.
+type List struct {
+	// KeyField is the field of each list element to compare against
+	// KeyValue.
+	KeyField string
+	// KeyValue is the value KeyField must equal for an element to match.
+	// In the keyField form, exactly one of KeyValue or Glob is set;
+	// neither is set when Index is.
+	KeyValue *string
+	// Glob, if true, matches every element of the list.
+	Glob bool
+
+	// Index, if set, selects the list element at this position, e.g.
+	// `[0]`.
+	Index *int
+	// IndexEnd, if set alongside Index, selects the range of elements
+	// from Index up to (and including) IndexEnd, e.g. `[0:2]`.
+	IndexEnd *int
+}
+
+// String implements Node.
+func (l *List) String() string {
+	if l.Index != nil {
+		if l.IndexEnd != nil {
+			return fmt.Sprintf("[%d:%d]", *l.Index, *l.IndexEnd)
+		}
+		return fmt.Sprintf("[%d]", *l.Index)
+	}
+
+	value := "*"
+	if !l.Glob && l.KeyValue != nil {
+		value = quoteIfNeeded(*l.KeyValue)
+	}
+	key := quoteIfNeeded(l.KeyField)
+	if isAllDigits(l.KeyField) {
+		// A bare integer would reparse as an index selector.
+		key = `"` + l.KeyField + `"`
+	}
+	return fmt.Sprintf("[%s: %s]", key, value)
+}
+
+// DeepCopyNode implements Node.
+func (l *List) DeepCopyNode() Node {
+	out := &List{KeyField: l.KeyField, Glob: l.Glob}
+	if l.KeyValue != nil {
+		v := *l.KeyValue
+		out.KeyValue = &v
+	}
+	if l.Index != nil {
+		i := *l.Index
+		out.Index = &i
+	}
+	if l.IndexEnd != nil {
+		i := *l.IndexEnd
+		out.IndexEnd = &i
+	}
+	return out
+}
+
+// Parse parses input as a Path. An empty input is valid and produces a
+// Path with no Nodes.
+func Parse(input string) (Path, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return Path{}, err
+	}
+	if len(tokens) == 0 {
+		return Path{}, nil
+	}
+
+	p := &tokenParser{tokens: tokens}
+	nodes, err := p.parsePath()
+	if err != nil {
+		return Path{}, err
+	}
+	return Path{Nodes: nodes}, nil
+}
+
+// joinNodes reconstructs the DSL representation of nodes. List nodes are
+// written directly after the Object they index into, with no separator;
+// every other adjacent pair of nodes is joined with ".".
+func joinNodes(nodes []Node) string {
+	var sb strings.Builder
+	for i, n := range nodes {
+		if i > 0 {
+			if _, isList := n.(*List); !isList {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteString(n.String())
+	}
+	return sb.String()
+}
+
+// quoteIfNeeded returns s unquoted if it is a valid bare identifier, and a
+// quoted, escaped form of s otherwise (e.g. empty strings, and strings
+// containing whitespace or DSL metacharacters).
+func quoteIfNeeded(s string) string {
+	if isIdentifier(s) {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\':
+			sb.WriteString(`\\`)
+		case '"':
+			sb.WriteString(`\"`)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// isIdentifier reports whether s can be written as a bare, unquoted
+// identifier.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !isIdentChar(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIdentChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_'
+}
+
+// tokenKind identifies the lexical class of a token.
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokString
+	tokDot
+	tokLBracket
+	tokRBracket
+	tokColon
+	tokStar
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+// lex tokenizes input, skipping insignificant whitespace between tokens.
+func lex(input string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(input)
+	i, n := 0, len(runes)
+
+	for i < n {
+		r := runes[i]
+
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i++
+		case r == '.':
+			tokens = append(tokens, token{kind: tokDot})
+			i++
+		case r == '[':
+			tokens = append(tokens, token{kind: tokLBracket})
+			i++
+		case r == ']':
+			tokens = append(tokens, token{kind: tokRBracket})
+			i++
+		case r == ':':
+			tokens = append(tokens, token{kind: tokColon})
+			i++
+		case r == '*':
+			tokens = append(tokens, token{kind: tokStar})
+			i++
+		case r == '"' || r == '\'':
+			value, end, err := lexQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokString, value: value})
+			i = end
+		case isIdentChar(r):
+			start := i
+			for i < n && isIdentChar(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, value: string(runes[start:i])})
+		default:
+			return nil, fmt.Errorf("%w: unexpected character %q", ErrUnexpectedToken, r)
+		}
+	}
+
+	return tokens, nil
+}
+
+// lexQuoted reads a quoted string starting at runes[start] (the opening
+// quote) and returns its unescaped value along with the index just past
+// the closing quote.
+func lexQuoted(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	i := start + 1
+	n := len(runes)
+
+	var sb strings.Builder
+	for i < n {
+		c := runes[i]
+
+		if c == '\\' && i+1 < n && (runes[i+1] == quote || runes[i+1] == '\\') {
+			sb.WriteRune(runes[i+1])
+			i += 2
+			continue
+		}
+
+		if c == quote {
+			return sb.String(), i + 1, nil
+		}
+
+		sb.WriteRune(c)
+		i++
+	}
+
+	return "", 0, fmt.Errorf("%w: unterminated quoted string", ErrUnexpectedToken)
+}
+
+// tokenParser is a recursive-descent parser over a fixed token slice.
+type tokenParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *tokenParser) atEOF() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *tokenParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *tokenParser) advance() token {
+	tok := p.tokens[p.pos]
+	p.pos++
+	return tok
+}
+
+// parsePath parses a full Path: an Object, optionally followed directly
+// by a List, repeated and separated by ".".
+func (p *tokenParser) parsePath() ([]Node, error) {
+	var nodes []Node
+
+	for {
+		tok := p.peek()
+		if tok.kind != tokIdent && tok.kind != tokString {
+			return nil, ErrUnexpectedToken
+		}
+		nodes = append(nodes, &Object{Reference: p.advance().value})
+
+		if !p.atEOF() && p.peek().kind == tokLBracket {
+			list, err := p.parseList()
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, list)
+		}
+
+		if p.atEOF() {
+			return nodes, nil
+		}
+
+		if p.peek().kind != tokDot {
+			return nil, ErrUnexpectedToken
+		}
+		p.advance()
+
+		if p.atEOF() {
+			return nil, ErrTrailingSeparator
+		}
+	}
+}
+
+// parseList parses a `[keyField: keyValue]`, `[keyField: *]`, `[index]`,
+// or `[index:indexEnd]` selector. The caller must have confirmed the next
+// token is "[".
+func (p *tokenParser) parseList() (*List, error) {
+	p.advance() // consume "["
+
+	if p.atEOF() {
+		return nil, ErrUnexpectedToken
+	}
+
+	// A bare, unquoted integer commits to the index/range form: once a
+	// selector starts with a digit it can't also be read as a keyField
+	// clause, so any deviation from here is a hard parse error rather
+	// than a fallback to the keyField grammar.
+	if p.peek().kind == tokIdent && isAllDigits(p.peek().value) {
+		return p.parseListIndex()
+	}
+
+	keyTok := p.peek()
+	if keyTok.kind != tokIdent && keyTok.kind != tokString {
+		return nil, ErrUnexpectedToken
+	}
+	p.advance()
+
+	if p.atEOF() || p.peek().kind != tokColon {
+		return nil, ErrUnexpectedToken
+	}
+	p.advance() // consume ":"
+
+	if p.atEOF() {
+		return nil, ErrUnexpectedToken
+	}
+
+	list := &List{KeyField: keyTok.value}
+
+	switch valueTok := p.peek(); valueTok.kind {
+	case tokStar:
+		list.Glob = true
+		p.advance()
+	case tokIdent, tokString:
+		value := valueTok.value
+		list.KeyValue = &value
+		p.advance()
+	default:
+		return nil, ErrUnexpectedToken
+	}
+
+	if p.atEOF() || p.peek().kind != tokRBracket {
+		return nil, ErrUnexpectedToken
+	}
+	p.advance() // consume "]"
+
+	return list, nil
+}
+
+// parseListIndex parses the `[index]`/`[index:indexEnd]` forms of a list
+// selector. The caller must have confirmed the next token is an unquoted,
+// all-digit identifier.
+func (p *tokenParser) parseListIndex() (*List, error) {
+	index, err := strconv.Atoi(p.advance().value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid index", ErrUnexpectedToken)
+	}
+	list := &List{Index: &index}
+
+	if !p.atEOF() && p.peek().kind == tokColon {
+		p.advance() // consume ":"
+
+		if p.atEOF() || p.peek().kind != tokIdent || !isAllDigits(p.peek().value) {
+			return nil, ErrUnexpectedToken
+		}
+		indexEnd, err := strconv.Atoi(p.advance().value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid index", ErrUnexpectedToken)
+		}
+		list.IndexEnd = &indexEnd
+	}
+
+	if p.atEOF() || p.peek().kind != tokRBracket {
+		return nil, ErrUnexpectedToken
+	}
+	p.advance() // consume "]"
+
+	return list, nil
+}
+
+// isAllDigits reports whether s is a non-empty run of decimal digits.
+func isAllDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -278,6 +278,53 @@ func TestParser(t *testing.T) {
 				&Object{Reference: `token-with-\embedded-backslash`},
 			},
 		},
+		{
+			// a bare integer in brackets selects by position
+			input: `spec.containers[0].image`,
+			expected: []Node{
+				&Object{Reference: "spec"},
+				&Object{Reference: "containers"},
+				&List{Index: intPtr(0)},
+				&Object{Reference: "image"},
+			},
+		},
+		{
+			// two bare integers separated by a colon select a range
+			input: `spec.containers[0:2]`,
+			expected: []Node{
+				&Object{Reference: "spec"},
+				&Object{Reference: "containers"},
+				&List{Index: intPtr(0), IndexEnd: intPtr(2)},
+			},
+		},
+		{
+			// leading zeros are valid indices
+			input: `spec.initContainers[007]`,
+			expected: []Node{
+				&Object{Reference: "spec"},
+				&Object{Reference: "initContainers"},
+				&List{Index: intPtr(7)},
+			},
+		},
+		{
+			// an index can't be combined with a keyField clause
+			input:   `spec.containers[0: foo]`,
+			wantErr: ErrUnexpectedToken,
+		},
+		{
+			// a range's second half must also be a bare integer
+			input:   `spec.containers[0: *]`,
+			wantErr: ErrUnexpectedToken,
+		},
+		{
+			// a quoted integer is a keyField, not an index
+			input: `spec.containers["0": "foo"]`,
+			expected: []Node{
+				&Object{Reference: "spec"},
+				&Object{Reference: "containers"},
+				&List{KeyField: "0", KeyValue: strPtr("foo")},
+			},
+		},
 	}
 
 	for i, tc := range tests {
@@ -330,6 +377,14 @@ func TestDeepCopy(t *testing.T) {
 			name:  "test list deepcopy with nil nexted pointer",
 			input: &List{KeyField: "much full", KeyValue: nil},
 		},
+		{
+			name:  "test list index deepcopy",
+			input: &List{Index: intPtr(0)},
+		},
+		{
+			name:  "test list index range deepcopy",
+			input: &List{Index: intPtr(0), IndexEnd: intPtr(2)},
+		},
 		{
 			name: "test path deepcopy",
 			input: &Path{
@@ -359,3 +414,7 @@ func TestDeepCopy(t *testing.T) {
 func strPtr(s string) *string {
 	return &s
 }
+
+func intPtr(i int) *int {
+	return &i
+}